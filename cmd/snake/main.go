@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/nsf/termbox-go"
+
+	"github.com/shadyashraf174/golang-test-web/game"
+	"github.com/shadyashraf174/golang-test-web/ws"
+)
+
+const (
+	foodChar   = '◆'
+	snakeChar  = '▣'
+	borderChar = '■'
+)
+
+// Color scheme
+const (
+	ColorSnake    = termbox.ColorGreen
+	ColorFood     = termbox.ColorRed
+	ColorBorder   = termbox.ColorCyan
+	ColorScore    = termbox.ColorWhite | termbox.AttrBold
+	ColorGameOver = termbox.ColorRed | termbox.AttrBold
+	ColorText     = termbox.ColorYellow
+)
+
+// client is the termbox snake binary, now a thin WebSocket client: the
+// lobby server ticks the authoritative game.Engine and streams State
+// snapshots down; the client only renders them and forwards input frames.
+type client struct {
+	conn     *ws.Conn
+	playerID string
+
+	stateCh  chan game.State
+	quitCh   chan struct{}
+	quitOnce sync.Once
+}
+
+func (c *client) quit() {
+	c.quitOnce.Do(func() { close(c.quitCh) })
+}
+
+func main() {
+	server := flag.String("server", "http://localhost:8080", "lobby server base URL")
+	join := flag.String("join", "", "join an existing lobby by passphrase instead of creating one")
+	flag.Parse()
+
+	lobbyID, playerID, err := connectLobby(*server, *join)
+	if err != nil {
+		fmt.Println("Error connecting to lobby server:", err)
+		return
+	}
+
+	wsURL, err := streamURL(*server, lobbyID, playerID)
+	if err != nil {
+		fmt.Println("Error building stream URL:", err)
+		return
+	}
+	conn, err := ws.Dial(wsURL)
+	if err != nil {
+		fmt.Println("Error connecting to lobby stream:", err)
+		return
+	}
+	defer conn.Close()
+
+	c := &client{
+		conn:     conn,
+		playerID: playerID,
+		stateCh:  make(chan game.State, 1),
+		quitCh:   make(chan struct{}),
+	}
+
+	if err := termbox.Init(); err != nil {
+		panic(err)
+	}
+	defer termbox.Close()
+
+	showWelcomeScreen()
+	go c.readStates()
+	go c.readInput()
+
+	c.drawLoop()
+}
+
+// connectLobby creates a new lobby, or joins one by passphrase if join is
+// non-empty, returning the lobby ID and this client's player ID.
+func connectLobby(server, join string) (lobbyID, playerID string, err error) {
+	if join == "" {
+		var created struct {
+			ID         string `json:"id"`
+			Passphrase string `json:"passphrase"`
+		}
+		if err := postJSON(server+"/lobbies", nil, &created); err != nil {
+			return "", "", fmt.Errorf("create lobby: %w", err)
+		}
+		join = created.Passphrase
+	}
+
+	var joined struct {
+		LobbyID  string `json:"lobby_id"`
+		PlayerID string `json:"player_id"`
+	}
+	if err := postJSON(server+"/lobbies/"+join+"/join", nil, &joined); err != nil {
+		return "", "", fmt.Errorf("join lobby: %w", err)
+	}
+
+	return joined.LobbyID, joined.PlayerID, nil
+}
+
+func postJSON(url string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	resp, err := http.Post(url, "application/json", reader)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// streamURL turns the server's http(s) base URL into the ws(s) URL for a
+// lobby's stream endpoint.
+func streamURL(server, lobbyID, playerID string) (string, error) {
+	u, err := url.Parse(server)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/lobbies/" + lobbyID + "/stream"
+	u.RawQuery = "player_id=" + playerID
+	return u.String(), nil
+}
+
+// readStates pulls state snapshots off the WebSocket and forwards each one
+// to drawLoop, dropping a stale undrawn snapshot rather than blocking.
+func (c *client) readStates() {
+	for {
+		msg, err := c.conn.ReadMessage()
+		if err != nil {
+			c.quit()
+			return
+		}
+
+		var state game.State
+		if err := json.Unmarshal(msg, &state); err != nil {
+			continue
+		}
+
+		select {
+		case <-c.stateCh:
+		default:
+		}
+		c.stateCh <- state
+	}
+}
+
+// readInput forwards arrow-key presses to the server as direction frames;
+// the server (not this client) is the authority on whether a move is legal.
+func (c *client) readInput() {
+	for {
+		ev := termbox.PollEvent()
+		if ev.Type != termbox.EventKey {
+			continue
+		}
+
+		var dir game.Direction
+		switch {
+		case ev.Key == termbox.KeyArrowUp:
+			dir = game.Up
+		case ev.Key == termbox.KeyArrowDown:
+			dir = game.Down
+		case ev.Key == termbox.KeyArrowLeft:
+			dir = game.Left
+		case ev.Key == termbox.KeyArrowRight:
+			dir = game.Right
+		case ev.Ch == 'q' || ev.Key == termbox.KeyEsc:
+			c.conn.Close()
+			c.quit()
+			return
+		default:
+			continue
+		}
+
+		frame, err := json.Marshal(struct {
+			Direction game.Direction `json:"direction"`
+		}{dir})
+		if err != nil {
+			continue
+		}
+		c.conn.WriteMessage(frame)
+	}
+}
+
+// drawLoop redraws the board every time a new state snapshot arrives.
+func (c *client) drawLoop() {
+	for {
+		select {
+		case state := <-c.stateCh:
+			c.draw(state)
+		case <-c.quitCh:
+			return
+		}
+	}
+}
+
+func showWelcomeScreen() {
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	drawCenteredText("SNAKE GAME", 5, ColorText)
+	drawCenteredText("Use arrow keys to move", 8, ColorText)
+	drawCenteredText("Collect the "+string(foodChar)+" to grow", 9, ColorFood)
+	drawCenteredText("Avoid walls and yourself!", 10, ColorText)
+	drawCenteredText("Press any key to start", 13, ColorText)
+	termbox.Flush()
+
+	// Wait for any key
+	for {
+		ev := termbox.PollEvent()
+		if ev.Type == termbox.EventKey {
+			return
+		}
+	}
+}
+
+func (c *client) draw(state game.State) {
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	drawBorder()
+	for id, p := range state.Players {
+		drawSnake(p.Snake, id == c.playerID)
+	}
+	drawFood(state.Food)
+
+	score := state.Players[c.playerID].Score
+	drawScore(score)
+
+	if state.GameOver {
+		drawGameOver(score)
+	}
+
+	termbox.Flush()
+}
+
+func drawBorder() {
+	// Top and bottom borders
+	for x := 0; x < game.BoardWidth+2; x++ {
+		termbox.SetCell(x, 0, borderChar, ColorBorder, termbox.ColorDefault)
+		termbox.SetCell(x, game.BoardHeight+1, borderChar, ColorBorder, termbox.ColorDefault)
+	}
+
+	// Side borders
+	for y := 1; y <= game.BoardHeight; y++ {
+		termbox.SetCell(0, y, borderChar, ColorBorder, termbox.ColorDefault)
+		termbox.SetCell(game.BoardWidth+1, y, borderChar, ColorBorder, termbox.ColorDefault)
+	}
+}
+
+// drawSnake draws one player's snake. own draws this client's own snake in
+// green and every other player's in yellow, so it stands out among them.
+func drawSnake(snake []game.Position, own bool) {
+	body, head := ColorSnake, termbox.ColorGreen|termbox.AttrBold
+	if !own {
+		body, head = termbox.ColorYellow, termbox.ColorYellow|termbox.AttrBold
+	}
+	for i, pos := range snake {
+		color := body
+		if i == 0 {
+			color = head
+		}
+		termbox.SetCell(pos.X+1, pos.Y+1, snakeChar, color, termbox.ColorDefault)
+	}
+}
+
+func drawFood(food game.Position) {
+	termbox.SetCell(food.X+1, food.Y+1, foodChar, ColorFood, termbox.ColorDefault)
+}
+
+func drawScore(score int) {
+	scoreText := fmt.Sprintf(" SCORE: %d ", score)
+	instructions := " PRESS Q TO QUIT "
+
+	// Draw score box
+	for i, ch := range scoreText {
+		termbox.SetCell(i+2, game.BoardHeight+3, ch, ColorScore, termbox.ColorBlue)
+	}
+
+	// Draw instructions
+	for i, ch := range instructions {
+		termbox.SetCell(game.BoardWidth+2-len(instructions)+i, game.BoardHeight+3, ch, ColorText, termbox.ColorBlue)
+	}
+}
+
+func drawGameOver(score int) {
+	message := []string{
+		"╔═════════════════════╗",
+		"║      GAME OVER      ║",
+		"║                     ║",
+		"║   FINAL SCORE: %3d  ║",
+		"║                     ║",
+		"║  PRESS Q TO QUIT    ║",
+		"╚═════════════════════╝",
+	}
+
+	yStart := (game.BoardHeight - len(message)) / 2
+	for i, line := range message {
+		if i == 3 {
+			line = fmt.Sprintf(line, score)
+		}
+		x := (game.BoardWidth-len(line))/2 + 1
+		for j, ch := range line {
+			termbox.SetCell(x+j, yStart+i, ch, ColorGameOver, termbox.ColorDefault)
+		}
+	}
+}
+
+func drawCenteredText(text string, y int, color termbox.Attribute) {
+	x := (game.BoardWidth-len(text))/2 + 1
+	for i, ch := range text {
+		termbox.SetCell(x+i, y, ch, color, termbox.ColorDefault)
+	}
+}
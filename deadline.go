@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// deadlineTimer is a one-shot cancellation signal whose Done channel closes
+// either when Stop is called or when its deadline elapses, whichever comes
+// first — the cancel-channel-plus-time.AfterFunc shape the net package uses
+// internally for read/write deadlines. It's the building block behind
+// requestContext below, and is reusable as-is by future streaming endpoints
+// that need a deadline but aren't naturally expressed as a single
+// context.Context.
+type deadlineTimer struct {
+	once sync.Once
+	done chan struct{}
+	t    *time.Timer
+}
+
+// newDeadlineTimer arms a deadlineTimer that fires after d. d <= 0 means no
+// deadline; Done only closes when Stop is called.
+func newDeadlineTimer(d time.Duration) *deadlineTimer {
+	dt := &deadlineTimer{done: make(chan struct{})}
+	if d > 0 {
+		dt.t = time.AfterFunc(d, dt.fire)
+	}
+	return dt
+}
+
+// Done returns the channel that closes on deadline or Stop.
+func (dt *deadlineTimer) Done() <-chan struct{} {
+	return dt.done
+}
+
+// Stop cancels the pending timer (if any) and closes Done.
+func (dt *deadlineTimer) Stop() {
+	if dt.t != nil {
+		dt.t.Stop()
+	}
+	dt.fire()
+}
+
+func (dt *deadlineTimer) fire() {
+	dt.once.Do(func() { close(dt.done) })
+}
+
+// requestContext derives a context.Context for a single handler's store
+// calls from r.Context(), so it's canceled if the client disconnects, and
+// also arms a deadlineTimer for routeDeadline so a store call that's merely
+// slow (not disconnected) still gets canceled instead of running forever.
+// The returned cancel must be called once the handler is done with ctx.
+func requestContext(r *http.Request, routeDeadline time.Duration) (ctx context.Context, cancel func()) {
+	dt := newDeadlineTimer(routeDeadline)
+	ctx, cancelCtx := context.WithCancel(r.Context())
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-dt.Done():
+			cancelCtx()
+		case <-done:
+		}
+	}()
+
+	return ctx, func() {
+		close(done)
+		dt.Stop()
+		cancelCtx()
+	}
+}
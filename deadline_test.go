@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDeadlineTimerFiresOnDeadline(t *testing.T) {
+	dt := newDeadlineTimer(10 * time.Millisecond)
+	select {
+	case <-dt.Done():
+	case <-time.After(time.Second):
+		t.Fatal("deadlineTimer.Done() never closed")
+	}
+}
+
+func TestDeadlineTimerStopWithoutFiring(t *testing.T) {
+	dt := newDeadlineTimer(time.Hour)
+	dt.Stop()
+	select {
+	case <-dt.Done():
+	default:
+		t.Fatal("Stop() did not close Done()")
+	}
+}
+
+func TestRequestContextCanceledByRouteDeadline(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+
+	ctx, cancel := requestContext(req, 10*time.Millisecond)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		if ctx.Err() != context.Canceled {
+			t.Fatalf("ctx.Err() = %v, want context.Canceled", ctx.Err())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("context was not canceled once the route deadline elapsed")
+	}
+}
+
+func TestRequestContextCanceledByClientDisconnect(t *testing.T) {
+	baseCtx, cancelBase := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/items", nil).WithContext(baseCtx)
+
+	ctx, cancel := requestContext(req, time.Hour)
+	defer cancel()
+
+	cancelBase()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context was not canceled when the request's own context was canceled")
+	}
+}
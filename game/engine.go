@@ -0,0 +1,293 @@
+// Package game holds the headless snake simulation. It has no dependency on
+// termbox or HTTP so it can be ticked either by the local terminal client or
+// by the authoritative lobby server.
+package game
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// BoardWidth and BoardHeight are the standard board dimensions used by the
+// lobby server when it creates an Engine, and by the termbox client when it
+// draws one — kept here so the two agree without either importing the
+// other.
+const (
+	BoardWidth  = 25
+	BoardHeight = 20
+)
+
+// Direction is one of the four cardinal directions the snake can move in.
+type Direction string
+
+const (
+	Up    Direction = "UP"
+	Down  Direction = "DOWN"
+	Left  Direction = "LEFT"
+	Right Direction = "RIGHT"
+)
+
+// opposite reports the direction that would immediately reverse a snake
+// into itself.
+func opposite(d Direction) Direction {
+	switch d {
+	case Up:
+		return Down
+	case Down:
+		return Up
+	case Left:
+		return Right
+	case Right:
+		return Left
+	default:
+		return ""
+	}
+}
+
+// Position is a single grid cell.
+type Position struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// PlayerState is one player's snake, as serialized to clients.
+type PlayerState struct {
+	Snake     []Position `json:"snake"`
+	Direction Direction  `json:"direction"`
+	Score     int        `json:"score"`
+	Alive     bool       `json:"alive"`
+}
+
+// State is the serializable snapshot broadcast to clients/spectators. It
+// carries every player's snake, keyed by player ID, not just one — this is
+// a shared board, not one snake per lobby.
+type State struct {
+	Players  map[string]PlayerState `json:"players"`
+	Food     Position               `json:"food"`
+	GameOver bool                   `json:"game_over"`
+}
+
+type playerState struct {
+	snake     []Position
+	direction Direction
+	score     int
+	alive     bool
+}
+
+// Engine is the headless, authoritative simulation for one game of snake,
+// shared by every player connected to a lobby. All methods are safe for
+// concurrent use so the lobby server can tick it from one goroutine while
+// input frames arrive from others.
+type Engine struct {
+	mu     sync.Mutex
+	width  int
+	height int
+
+	players map[string]*playerState
+	food    Position
+}
+
+// NewEngine returns a freshly reset engine for a width x height board, with
+// no players yet.
+func NewEngine(width, height int) *Engine {
+	e := &Engine{width: width, height: height, players: make(map[string]*playerState)}
+	e.placeFoodLocked()
+	return e
+}
+
+// AddPlayer spawns a new snake for id at a free cell. Calling it again for
+// an id already in play (e.g. a reconnect) replaces that player's snake.
+func (e *Engine) AddPlayer(id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.players[id] = &playerState{
+		snake:     []Position{e.freeCellLocked()},
+		direction: Right,
+		alive:     true,
+	}
+}
+
+// RemovePlayer drops id's snake from the simulation, e.g. once its
+// connection closes.
+func (e *Engine) RemovePlayer(id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.players, id)
+}
+
+// SetDirection applies player id's requested direction change. It rejects a
+// reversal straight into that player's own body and reports whether the
+// direction was accepted, so callers (e.g. the lobby server) can drop
+// illegal or unknown-player input frames instead of silently applying them.
+func (e *Engine) SetDirection(id string, d Direction) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	p, ok := e.players[id]
+	if !ok || !p.alive {
+		return false
+	}
+	switch d {
+	case Up, Down, Left, Right:
+	default:
+		return false
+	}
+	if d == opposite(p.direction) {
+		return false
+	}
+	p.direction = d
+	return true
+}
+
+// Tick advances every alive player's snake by one step: walls, a player's
+// own body, and every other player's body are all lethal. Two snakes
+// stepping onto the same cell this tick both die. It's a no-op for players
+// already dead, and leaves a dead player's last snake position in place so
+// spectators still see where they went down.
+func (e *Engine) Tick() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	newHeads := make(map[string]Position, len(e.players))
+	for id, p := range e.players {
+		if !p.alive {
+			continue
+		}
+		newHeads[id] = step(p.snake[0], p.direction)
+	}
+
+	dead := make(map[string]bool, len(newHeads))
+	for id, head := range newHeads {
+		if e.collidesLocked(id, head, newHeads) {
+			dead[id] = true
+		}
+	}
+
+	for id, head := range newHeads {
+		p := e.players[id]
+		if dead[id] {
+			p.alive = false
+			continue
+		}
+		p.snake = append([]Position{head}, p.snake...)
+		if head == e.food {
+			p.score += 10
+			e.placeFoodLocked()
+		} else {
+			p.snake = p.snake[:len(p.snake)-1]
+		}
+	}
+}
+
+func step(head Position, d Direction) Position {
+	next := head
+	switch d {
+	case Up:
+		next.Y--
+	case Down:
+		next.Y++
+	case Left:
+		next.X--
+	case Right:
+		next.X++
+	}
+	return next
+}
+
+// collidesLocked reports whether id's snake head landing on next is lethal:
+// off the board, inside any alive player's current body (id's own tail cell
+// is exempt unless it's about to grow), or a head-on crash into another
+// snake moving onto the same cell this tick.
+func (e *Engine) collidesLocked(id string, next Position, newHeads map[string]Position) bool {
+	if next.X < 0 || next.X >= e.width || next.Y < 0 || next.Y >= e.height {
+		return true
+	}
+
+	for otherID, p := range e.players {
+		if !p.alive {
+			continue
+		}
+		body := p.snake
+		if otherID == id && next != e.food {
+			// The tail cell vacates this tick unless the snake is growing,
+			// so colliding with it isn't a crash.
+			body = body[:len(body)-1]
+		}
+		for _, segment := range body {
+			if segment == next {
+				return true
+			}
+		}
+	}
+
+	for otherID, otherNext := range newHeads {
+		if otherID != id && otherNext == next {
+			return true
+		}
+	}
+	return false
+}
+
+// PlaceFood drops food on a cell no player's snake currently occupies.
+func (e *Engine) PlaceFood() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.placeFoodLocked()
+}
+
+func (e *Engine) placeFoodLocked() {
+	e.food = e.freeCellLocked()
+}
+
+// freeCellLocked returns a random cell no player's snake currently occupies.
+func (e *Engine) freeCellLocked() Position {
+	for {
+		candidate := Position{X: rand.Intn(e.width), Y: rand.Intn(e.height)}
+		occupied := false
+		for _, p := range e.players {
+			for _, segment := range p.snake {
+				if segment == candidate {
+					occupied = true
+					break
+				}
+			}
+			if occupied {
+				break
+			}
+		}
+		if !occupied {
+			return candidate
+		}
+	}
+}
+
+// State returns a point-in-time snapshot safe to serialize and send to
+// clients. GameOver reports whether every player currently in the game has
+// died; a lobby with no players yet is not over.
+func (e *Engine) State() State {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	players := make(map[string]PlayerState, len(e.players))
+	anyAlive := false
+	for id, p := range e.players {
+		snake := make([]Position, len(p.snake))
+		copy(snake, p.snake)
+		players[id] = PlayerState{
+			Snake:     snake,
+			Direction: p.direction,
+			Score:     p.score,
+			Alive:     p.alive,
+		}
+		if p.alive {
+			anyAlive = true
+		}
+	}
+
+	return State{
+		Players:  players,
+		Food:     e.food,
+		GameOver: len(players) > 0 && !anyAlive,
+	}
+}
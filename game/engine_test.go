@@ -0,0 +1,153 @@
+package game
+
+import "testing"
+
+func TestEngineEachPlayerControlsOwnSnake(t *testing.T) {
+	e := NewEngine(10, 10)
+	e.AddPlayer("alice")
+	e.AddPlayer("bob")
+
+	e.mu.Lock()
+	e.players["alice"].snake = []Position{{X: 2, Y: 2}}
+	e.players["alice"].direction = Right
+	e.players["bob"].snake = []Position{{X: 7, Y: 7}}
+	e.players["bob"].direction = Up
+	e.food = Position{X: -1, Y: -1} // off-board so nobody eats this tick
+	e.mu.Unlock()
+
+	e.Tick()
+
+	state := e.State()
+	if got := state.Players["alice"].Snake[0]; got != (Position{X: 3, Y: 2}) {
+		t.Fatalf("alice head = %+v, want {3 2}", got)
+	}
+	if got := state.Players["bob"].Snake[0]; got != (Position{X: 7, Y: 6}) {
+		t.Fatalf("bob head = %+v, want {7 6}", got)
+	}
+}
+
+func TestEngineMoveGrowsOnFood(t *testing.T) {
+	e := NewEngine(10, 10)
+	e.AddPlayer("alice")
+
+	e.mu.Lock()
+	e.players["alice"].snake = []Position{{X: 5, Y: 5}}
+	e.players["alice"].direction = Right
+	e.food = Position{X: 6, Y: 5}
+	e.mu.Unlock()
+
+	e.Tick()
+
+	state := e.State()
+	alice := state.Players["alice"]
+	if len(alice.Snake) != 2 {
+		t.Fatalf("snake length = %d, want 2 after eating", len(alice.Snake))
+	}
+	if alice.Snake[0] != (Position{X: 6, Y: 5}) {
+		t.Fatalf("head = %+v, want {6 5}", alice.Snake[0])
+	}
+	if alice.Score != 10 {
+		t.Fatalf("score = %d, want 10", alice.Score)
+	}
+	if state.Food == (Position{X: 6, Y: 5}) {
+		t.Fatal("new food placed on the old food's cell")
+	}
+}
+
+func TestEngineWallCollisionKillsOnlyThatPlayer(t *testing.T) {
+	e := NewEngine(10, 10)
+	e.AddPlayer("alice")
+	e.AddPlayer("bob")
+
+	e.mu.Lock()
+	e.players["alice"].snake = []Position{{X: 0, Y: 5}}
+	e.players["alice"].direction = Left
+	e.players["bob"].snake = []Position{{X: 5, Y: 5}}
+	e.players["bob"].direction = Right
+	e.food = Position{X: -1, Y: -1}
+	e.mu.Unlock()
+
+	e.Tick()
+
+	state := e.State()
+	if state.Players["alice"].Alive {
+		t.Fatal("expected alice to die after moving off the board")
+	}
+	if !state.Players["bob"].Alive {
+		t.Fatal("bob's unrelated move should not have killed him")
+	}
+	if state.GameOver {
+		t.Fatal("game should not be over while bob is still alive")
+	}
+}
+
+func TestEngineSelfCollision(t *testing.T) {
+	e := NewEngine(10, 10)
+	e.AddPlayer("alice")
+
+	e.mu.Lock()
+	e.players["alice"].snake = []Position{{X: 5, Y: 5}, {X: 6, Y: 5}, {X: 5, Y: 4}, {X: 4, Y: 5}, {X: 4, Y: 4}, {X: 5, Y: 6}}
+	e.players["alice"].direction = Up
+	e.food = Position{X: -1, Y: -1}
+	e.mu.Unlock()
+
+	e.Tick()
+
+	if state := e.State(); state.Players["alice"].Alive {
+		t.Fatal("expected alice to die after running into her own body")
+	}
+}
+
+func TestEngineCollidingWithAnotherPlayerKillsBoth(t *testing.T) {
+	e := NewEngine(10, 10)
+	e.AddPlayer("alice")
+	e.AddPlayer("bob")
+
+	e.mu.Lock()
+	e.players["alice"].snake = []Position{{X: 4, Y: 5}, {X: 3, Y: 5}}
+	e.players["alice"].direction = Right
+	e.players["bob"].snake = []Position{{X: 6, Y: 5}, {X: 7, Y: 5}}
+	e.players["bob"].direction = Left
+	e.food = Position{X: -1, Y: -1}
+	e.mu.Unlock()
+
+	e.Tick()
+
+	state := e.State()
+	if state.Players["alice"].Alive || state.Players["bob"].Alive {
+		t.Fatal("expected a head-on collision to kill both players")
+	}
+	if !state.GameOver {
+		t.Fatal("expected the game to be over once every player is dead")
+	}
+}
+
+func TestGameOverRequiresAtLeastOnePlayer(t *testing.T) {
+	e := NewEngine(10, 10)
+	if state := e.State(); state.GameOver {
+		t.Fatal("an empty lobby should not report game over")
+	}
+}
+
+func TestSetDirectionRejectsReversal(t *testing.T) {
+	e := NewEngine(10, 10)
+	e.AddPlayer("alice")
+
+	e.mu.Lock()
+	e.players["alice"].direction = Right
+	e.mu.Unlock()
+
+	if ok := e.SetDirection("alice", Left); ok {
+		t.Fatal("SetDirection accepted a direct reversal into the snake's own body")
+	}
+	if ok := e.SetDirection("alice", Up); !ok {
+		t.Fatal("SetDirection rejected a legal turn")
+	}
+}
+
+func TestSetDirectionRejectsUnknownPlayer(t *testing.T) {
+	e := NewEngine(10, 10)
+	if ok := e.SetDirection("nobody", Up); ok {
+		t.Fatal("SetDirection accepted a direction change for a player not in the game")
+	}
+}
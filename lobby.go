@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shadyashraf174/golang-test-web/game"
+	"github.com/shadyashraf174/golang-test-web/router"
+	"github.com/shadyashraf174/golang-test-web/ws"
+)
+
+const lobbyTickRate = 150 * time.Millisecond
+
+// Lobby is one authoritative game session: the engine the server ticks plus
+// the set of WebSocket connections receiving its state snapshots.
+type Lobby struct {
+	ID         string
+	Passphrase string
+	Engine     *game.Engine
+
+	mu        sync.Mutex
+	players   map[string]*ws.Conn
+	done      chan struct{}
+	tickStart sync.Once // guards starting the tick loop on the first join
+}
+
+// LobbyManager creates, joins and ticks lobbies, and records finished games
+// to a leaderboard store.
+type LobbyManager struct {
+	mu       sync.Mutex
+	byID     map[string]*Lobby
+	byPhrase map[string]*Lobby
+
+	// leaderboard persists finished games' scores. It's a Store like the
+	// items API's, but a separate instance: leaderboard entries aren't
+	// items and shouldn't show up in GET /items.
+	leaderboard Store
+}
+
+// NewLobbyManager returns a manager that persists leaderboard entries
+// through leaderboard.
+func NewLobbyManager(leaderboard Store) *LobbyManager {
+	return &LobbyManager{
+		byID:        make(map[string]*Lobby),
+		byPhrase:    make(map[string]*Lobby),
+		leaderboard: leaderboard,
+	}
+}
+
+func newToken(n int) (string, error) {
+	raw := make([]byte, n)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// create registers a new lobby under a fresh UUID-like ID and join
+// passphrase. Its tick loop doesn't start until the first player connects
+// (see startTicking), so an empty lobby doesn't run down the clock — or end
+// up on the leaderboard — before anyone joins.
+func (m *LobbyManager) create() (*Lobby, error) {
+	id, err := newToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("generate lobby id: %w", err)
+	}
+	phrase, err := newToken(4)
+	if err != nil {
+		return nil, fmt.Errorf("generate passphrase: %w", err)
+	}
+
+	lobby := &Lobby{
+		ID:         id,
+		Passphrase: phrase,
+		Engine:     game.NewEngine(game.BoardWidth, game.BoardHeight),
+		players:    make(map[string]*ws.Conn),
+		done:       make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	m.byID[id] = lobby
+	m.byPhrase[phrase] = lobby
+	m.mu.Unlock()
+
+	return lobby, nil
+}
+
+// startTicking starts lobby's tick loop the first time it's called for that
+// lobby; later calls (every subsequent player joining the same lobby) are
+// no-ops.
+func (m *LobbyManager) startTicking(lobby *Lobby) {
+	lobby.tickStart.Do(func() {
+		go m.run(lobby)
+	})
+}
+
+// run ticks lobby's engine until the game ends, broadcasting a snapshot to
+// every connected player/spectator after each tick.
+func (m *LobbyManager) run(lobby *Lobby) {
+	ticker := time.NewTicker(lobbyTickRate)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			lobby.Engine.Tick()
+			state := lobby.Engine.State()
+			lobby.broadcast(state)
+			if state.GameOver {
+				m.recordResult(lobby, state)
+				return
+			}
+		case <-lobby.done:
+			return
+		}
+	}
+}
+
+// recordResult persists every player's final score to the leaderboard store
+// (Name holds "<lobby ID>:<player ID>", Price the score — it reuses the
+// Item shape rather than introducing a second record type for one extra
+// field).
+func (m *LobbyManager) recordResult(lobby *Lobby, state game.State) {
+	for playerID, p := range state.Players {
+		item := Item{Name: lobby.ID + ":" + playerID, Price: p.Score}
+		if _, err := m.leaderboard.Create(context.Background(), item); err != nil {
+			fmt.Println("Error recording lobby result:", err)
+		}
+	}
+}
+
+func (l *Lobby) broadcast(state game.State) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for id, conn := range l.players {
+		if err := conn.WriteMessage(data); err != nil {
+			conn.Close()
+			delete(l.players, id)
+		}
+	}
+}
+
+func (l *Lobby) addPlayer(id string, conn *ws.Conn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.players[id] = conn
+}
+
+func (l *Lobby) removePlayer(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.players, id)
+}
+
+func (m *LobbyManager) lobbyByID(id string) (*Lobby, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lobby, ok := m.byID[id]
+	return lobby, ok
+}
+
+func (m *LobbyManager) lobbyByPhrase(phrase string) (*Lobby, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lobby, ok := m.byPhrase[phrase]
+	return lobby, ok
+}
+
+// handleLobbies handles POST /lobbies, creating a new lobby.
+func (m *LobbyManager) handleLobbies(c *router.Context) {
+	lobby, err := m.create()
+	if err != nil {
+		http.Error(c.Writer, "Failed to create lobby", http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", "application/json")
+	c.Writer.WriteHeader(http.StatusCreated)
+	json.NewEncoder(c.Writer).Encode(struct {
+		ID         string `json:"id"`
+		Passphrase string `json:"passphrase"`
+	}{lobby.ID, lobby.Passphrase})
+}
+
+// handleJoin handles POST /lobbies/:phrase/join, returning the lobby's ID
+// alongside a player ID — the lobby was looked up by passphrase, but the
+// stream endpoint is keyed by ID, so the client needs both.
+func (m *LobbyManager) handleJoin(c *router.Context) {
+	phrase := c.Param("phrase")
+	lobby, ok := m.lobbyByPhrase(phrase)
+	if !ok {
+		http.Error(c.Writer, "Lobby not found", http.StatusNotFound)
+		return
+	}
+
+	playerID, err := newToken(8)
+	if err != nil {
+		http.Error(c.Writer, "Failed to allocate player id", http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(c.Writer).Encode(struct {
+		LobbyID  string `json:"lobby_id"`
+		PlayerID string `json:"player_id"`
+	}{lobby.ID, playerID})
+}
+
+// handleStream handles GET /lobbies/:id/stream, upgrading to a WebSocket
+// that carries JSON state snapshots to the client and accepts JSON
+// direction frames in the other direction.
+func (m *LobbyManager) handleStream(c *router.Context) {
+	id := c.Param("id")
+	lobby, ok := m.lobbyByID(id)
+	if !ok {
+		http.Error(c.Writer, "Lobby not found", http.StatusNotFound)
+		return
+	}
+
+	conn, err := ws.Upgrade(c.Writer, c.Request)
+	if err != nil {
+		http.Error(c.Writer, "WebSocket upgrade failed", http.StatusBadRequest)
+		return
+	}
+	defer conn.Close()
+
+	playerID := c.Request.URL.Query().Get("player_id")
+	if playerID == "" {
+		playerID, err = newToken(8)
+		if err != nil {
+			return
+		}
+	}
+	lobby.addPlayer(playerID, conn)
+	lobby.Engine.AddPlayer(playerID)
+	defer lobby.removePlayer(playerID)
+	defer func() {
+		// A dead player's snake and score stay in the engine after they
+		// disconnect, so handleStats and recordResult still see them.
+		if lobby.Engine.State().Players[playerID].Alive {
+			lobby.Engine.RemovePlayer(playerID)
+		}
+	}()
+	m.startTicking(lobby)
+
+	for {
+		msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var frame struct {
+			Direction game.Direction `json:"direction"`
+		}
+		if err := json.Unmarshal(msg, &frame); err != nil {
+			continue
+		}
+		// Illegal direction changes (including reversals into the snake's
+		// own body) are rejected inside the engine, not trusted from the
+		// client.
+		lobby.Engine.SetDirection(playerID, frame.Direction)
+	}
+}
+
+// handleStats handles GET /lobbies/:id/stats, returning the final scores as
+// JSON once the lobby's game has ended. While the game is still in
+// progress it reports 409 Conflict rather than a snapshot of the
+// in-progress scores, since those aren't final and recordResult hasn't run
+// yet.
+func (m *LobbyManager) handleStats(c *router.Context) {
+	id := c.Param("id")
+	lobby, ok := m.lobbyByID(id)
+	if !ok {
+		http.Error(c.Writer, "Lobby not found", http.StatusNotFound)
+		return
+	}
+
+	state := lobby.Engine.State()
+	if !state.GameOver {
+		http.Error(c.Writer, "Game still in progress", http.StatusConflict)
+		return
+	}
+
+	scores := make(map[string]int, len(state.Players))
+	for playerID, p := range state.Players {
+		scores[playerID] = p.Score
+	}
+
+	c.Writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(c.Writer).Encode(struct {
+		LobbyID string         `json:"lobby_id"`
+		Scores  map[string]int `json:"scores"`
+	}{id, scores})
+}
+
+// handleLeaderboard handles GET /leaderboard, returning finished lobbies'
+// scores sorted highest first.
+func (m *LobbyManager) handleLeaderboard(c *router.Context) {
+	ctx, cancel := requestContext(c.Request, routeDeadline)
+	defer cancel()
+
+	entries, err := m.leaderboard.List(ctx)
+	if err != nil {
+		http.Error(c.Writer, "Failed to load leaderboard", http.StatusInternalServerError)
+		return
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Price > entries[j].Price })
+
+	c.Writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(c.Writer).Encode(entries)
+}
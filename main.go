@@ -1,11 +1,19 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"net/http"
-	"strconv"
-	"sync"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/shadyashraf174/golang-test-web/router"
 )
 
 // Item represents a simple data structure
@@ -15,178 +23,231 @@ type Item struct {
 	Price int    `json:"price"`
 }
 
-var (
-	items = make(map[int]Item) // In-memory storage for items
-	mu    sync.Mutex           // Mutex to handle concurrent access to the items map
-	idSeq = 1                  // Sequence for generating unique IDs
-)
+// store backs the items handlers below; see store.go for the Store
+// interface and its in-memory/file-backed implementations.
+var store Store
+
+// routeDeadline bounds how long a single handler's store calls may run; see
+// deadline.go's requestContext.
+var routeDeadline = 5 * time.Second
+
+// newStore builds the Store selected by -store (or the STORE env var, which
+// the flag defaults from), one of "memory" or "file".
+func newStore(kind, stateDir string) (Store, error) {
+	switch kind {
+	case "memory":
+		return NewMemoryStore(), nil
+	case "file":
+		return NewFileStore(stateDir)
+	default:
+		return nil, fmt.Errorf("unknown -store %q (want \"memory\" or \"file\")", kind)
+	}
+}
 
 func main() {
-	// Serve the frontend HTML file
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		http.ServeFile(w, r, "index.html")
-	})
-
-	// Define API routes
-	http.HandleFunc("/items", handleItems) // GET and POST
-	http.HandleFunc("/items/", handleItem) // GET, PUT, DELETE for specific item
+	storeKind := os.Getenv("STORE")
+	if storeKind == "" {
+		storeKind = "memory"
+	}
+	stateDir := os.Getenv("STATE_DIR")
+	if stateDir == "" {
+		stateDir = "./data"
+	}
 
-	// Start the server
-	fmt.Println("Server is running on http://localhost:8080")
-	if err := http.ListenAndServe(":8080", nil); err != nil {
-		fmt.Println("Error starting server:", err)
+	var shutdownGrace time.Duration
+	var rps, burst, perKeyRPS float64
+	flag.StringVar(&storeKind, "store", storeKind, `storage backend: "memory" or "file"`)
+	flag.StringVar(&stateDir, "state-dir", stateDir, `directory for the file store's mutation log`)
+	flag.DurationVar(&routeDeadline, "route-deadline", routeDeadline, "max time a single request's store calls may run")
+	flag.DurationVar(&shutdownGrace, "shutdown-grace", 10*time.Second, "time to wait for in-flight requests to finish on shutdown")
+	flag.Float64Var(&rps, "rps", 100, "global request budget, in requests/sec")
+	flag.Float64Var(&burst, "burst", 20, "burst size for the global and per-client token buckets")
+	flag.Float64Var(&perKeyRPS, "per-key-rps", 10, "per-client (API key or IP) request budget, in requests/sec")
+	flag.Parse()
+
+	s, err := newStore(storeKind, stateDir)
+	if err != nil {
+		fmt.Println("Error initializing store:", err)
+		os.Exit(1)
 	}
-}
+	store = s
 
-// handleItems handles GET and POST requests for /items
-func handleItems(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		getItems(w, r)
-	case http.MethodPost:
-		createItem(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// The leaderboard gets its own store instance (a separate file/namespace
+	// for the "file" backend) so finished lobbies' scores never show up in
+	// GET /items.
+	leaderboardStore, err := newStore(storeKind, filepath.Join(stateDir, "leaderboard"))
+	if err != nil {
+		fmt.Println("Error initializing leaderboard store:", err)
+		os.Exit(1)
 	}
-}
 
-// handleItem handles GET, PUT, and DELETE requests for /items/{id}
-func handleItem(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		getItem(w, r)
-	case http.MethodPut:
-		updateItem(w, r)
-	case http.MethodDelete:
-		deleteItem(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	lobbies := NewLobbyManager(leaderboardStore)
+	limiter := NewLimiter(rps, burst, perKeyRPS)
+
+	r := router.New()
+	r.Use(router.Recovery(), router.Logger(), router.CORS(), router.Gzip(), limiter.Middleware())
+
+	// Serve the frontend HTML file
+	r.GET("/", func(c *router.Context) {
+		http.ServeFile(c.Writer, c.Request, "index.html")
+	})
+
+	// Items API
+	r.GET("/items", getItems)
+	r.POST("/items", createItem)
+	r.GET("/items/:id", getItem)
+	r.PUT("/items/:id", updateItem)
+	r.DELETE("/items/:id", deleteItem)
+
+	// Multiplayer snake lobbies
+	r.POST("/lobbies", lobbies.handleLobbies)
+	r.POST("/lobbies/:phrase/join", lobbies.handleJoin)
+	r.GET("/lobbies/:id/stream", lobbies.handleStream)
+	r.GET("/lobbies/:id/stats", lobbies.handleStats)
+	r.GET("/leaderboard", lobbies.handleLeaderboard)
+
+	r.GET("/metrics", limiter.handleMetrics)
+
+	srv := &http.Server{Addr: ":8080", Handler: r}
+
+	// Start the server in the background so this goroutine can wait for a
+	// shutdown signal.
+	go func() {
+		fmt.Println("Server is running on http://localhost:8080")
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Println("Error starting server:", err)
+		}
+	}()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	<-sigCh
+
+	fmt.Println("Shutting down, draining in-flight requests...")
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		fmt.Println("Error during shutdown:", err)
 	}
 }
 
 // getItems returns a list of all items
-func getItems(w http.ResponseWriter, r *http.Request) {
-	mu.Lock()
-	defer mu.Unlock()
+func getItems(c *router.Context) {
+	ctx, cancel := requestContext(c.Request, routeDeadline)
+	defer cancel()
 
-	// Convert items map to a slice
-	itemList := make([]Item, 0, len(items))
-	for _, item := range items {
-		itemList = append(itemList, item)
+	itemList, err := store.List(ctx)
+	if err != nil {
+		http.Error(c.Writer, "Failed to list items", http.StatusInternalServerError)
+		return
 	}
 
 	// Return JSON response
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(itemList)
+	c.Writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(c.Writer).Encode(itemList)
 }
 
 // createItem adds a new item
-func createItem(w http.ResponseWriter, r *http.Request) {
+func createItem(c *router.Context) {
 	var newItem Item
-	err := json.NewDecoder(r.Body).Decode(&newItem)
+	err := json.NewDecoder(c.Request.Body).Decode(&newItem)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		http.Error(c.Writer, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	ctx, cancel := requestContext(c.Request, routeDeadline)
+	defer cancel()
 
-	// Assign a unique ID and add to the map
-	newItem.ID = idSeq
-	items[idSeq] = newItem
-	idSeq++
+	newItem, err = store.Create(ctx, newItem)
+	if err != nil {
+		http.Error(c.Writer, "Failed to create item", http.StatusInternalServerError)
+		return
+	}
 
 	// Return JSON response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(newItem)
+	c.Writer.Header().Set("Content-Type", "application/json")
+	c.Writer.WriteHeader(http.StatusCreated)
+	json.NewEncoder(c.Writer).Encode(newItem)
 }
 
 // getItem returns a specific item by ID
-func getItem(w http.ResponseWriter, r *http.Request) {
-	// Extract ID from the URL
-	idStr := r.URL.Path[len("/items/"):]
-	id, err := strconv.Atoi(idStr)
+func getItem(c *router.Context) {
+	id, err := c.ParamInt("id")
 	if err != nil {
-		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		http.Error(c.Writer, "Invalid item ID", http.StatusBadRequest)
 		return
 	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	ctx, cancel := requestContext(c.Request, routeDeadline)
+	defer cancel()
 
-	// Find the item
-	item, exists := items[id]
-	if !exists {
-		http.Error(w, "Item not found", http.StatusNotFound)
+	item, err := store.Get(ctx, id)
+	if err == ErrNotFound {
+		http.Error(c.Writer, "Item not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(c.Writer, "Failed to get item", http.StatusInternalServerError)
 		return
 	}
 
 	// Return JSON response
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(item)
+	c.Writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(c.Writer).Encode(item)
 }
 
 // updateItem updates an existing item
-func updateItem(w http.ResponseWriter, r *http.Request) {
-	// Extract ID from the URL
-	idStr := r.URL.Path[len("/items/"):]
-	id, err := strconv.Atoi(idStr)
+func updateItem(c *router.Context) {
+	id, err := c.ParamInt("id")
 	if err != nil {
-		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		http.Error(c.Writer, "Invalid item ID", http.StatusBadRequest)
 		return
 	}
 
 	var updatedItem Item
-	err = json.NewDecoder(r.Body).Decode(&updatedItem)
+	err = json.NewDecoder(c.Request.Body).Decode(&updatedItem)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		http.Error(c.Writer, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	ctx, cancel := requestContext(c.Request, routeDeadline)
+	defer cancel()
 
-	// Check if the item exists
-	_, exists := items[id]
-	if !exists {
-		http.Error(w, "Item not found", http.StatusNotFound)
+	updatedItem, err = store.Update(ctx, id, updatedItem)
+	if err == ErrNotFound {
+		http.Error(c.Writer, "Item not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(c.Writer, "Failed to update item", http.StatusInternalServerError)
 		return
 	}
 
-	// Update the item
-	updatedItem.ID = id
-	items[id] = updatedItem
-
 	// Return JSON response
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(updatedItem)
+	c.Writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(c.Writer).Encode(updatedItem)
 }
 
 // deleteItem deletes an item by ID
-func deleteItem(w http.ResponseWriter, r *http.Request) {
-	// Extract ID from the URL
-	idStr := r.URL.Path[len("/items/"):]
-	id, err := strconv.Atoi(idStr)
+func deleteItem(c *router.Context) {
+	id, err := c.ParamInt("id")
 	if err != nil {
-		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		http.Error(c.Writer, "Invalid item ID", http.StatusBadRequest)
 		return
 	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	ctx, cancel := requestContext(c.Request, routeDeadline)
+	defer cancel()
 
-	// Check if the item exists
-	_, exists := items[id]
-	if !exists {
-		http.Error(w, "Item not found", http.StatusNotFound)
+	err = store.Delete(ctx, id)
+	if err == ErrNotFound {
+		http.Error(c.Writer, "Item not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(c.Writer, "Failed to delete item", http.StatusInternalServerError)
 		return
 	}
 
-	// Delete the item
-	delete(items, id)
-
 	// Return success response
-	w.WriteHeader(http.StatusNoContent)
+	c.Writer.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shadyashraf174/golang-test-web/router"
+)
+
+// tokenBucket is a leaky-bucket limiter: it holds at most burst tokens,
+// refilling at rps tokens/sec, and Take reports whether a token was
+// available rather than blocking the caller — an HTTP handler can't afford
+// to sit in limiter.Take() the way a batch job can.
+type tokenBucket struct {
+	mu         sync.Mutex
+	rps        float64
+	burst      float64
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(rps, burst float64) *tokenBucket {
+	return &tokenBucket{rps: rps, burst: burst, tokens: burst, lastRefill: time.Now()}
+}
+
+// idleSince reports whether this bucket hasn't been used (refilled) since
+// cutoff, for the Limiter janitor's eviction sweep.
+func (b *tokenBucket) idleSince(cutoff time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastRefill.Before(cutoff)
+}
+
+// Take reports whether a token was available and, if so, consumes it.
+func (b *tokenBucket) Take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.lastRefill = now
+
+	b.tokens += elapsed * b.rps
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// rateLimitMetrics counts requests by outcome for the /metrics endpoint.
+type rateLimitMetrics struct {
+	accepted  atomic.Int64
+	throttled atomic.Int64 // blocked by a per-client quota
+	rejected  atomic.Int64 // blocked by the global quota
+}
+
+// perKeyIdleTTL and perKeySweepInterval bound the size of Limiter.perKey: a
+// client's bucket is evicted once it's gone unused for perKeyIdleTTL, so an
+// attacker cycling through API keys or source ports can't grow the map
+// without bound.
+const (
+	perKeyIdleTTL       = 10 * time.Minute
+	perKeySweepInterval = time.Minute
+)
+
+// Limiter gates requests with one global token bucket plus one per-client
+// bucket (keyed by API key if present, else by IP), the same two-tier shape
+// mchess's HostGameHandler uses limiter.Take() for before touching shared
+// state.
+type Limiter struct {
+	global *tokenBucket
+
+	perKeyRPS   float64
+	perKeyBurst float64
+	mu          sync.Mutex
+	perKey      map[string]*tokenBucket
+
+	metrics rateLimitMetrics
+}
+
+// NewLimiter builds a Limiter with a global rps/burst budget and a
+// per-client budget of perKeyRPS requests/sec (sharing the same burst). It
+// also starts a background sweep that evicts idle per-client buckets.
+func NewLimiter(rps, burst, perKeyRPS float64) *Limiter {
+	l := &Limiter{
+		global:      newTokenBucket(rps, burst),
+		perKeyRPS:   perKeyRPS,
+		perKeyBurst: burst,
+		perKey:      make(map[string]*tokenBucket),
+	}
+	go l.sweepIdleKeys()
+	return l
+}
+
+// sweepIdleKeys periodically evicts per-client buckets that haven't been
+// used in perKeyIdleTTL, so perKey doesn't grow without bound as clients
+// come and go.
+func (l *Limiter) sweepIdleKeys() {
+	ticker := time.NewTicker(perKeySweepInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		l.evictIdleKeys(time.Now().Add(-perKeyIdleTTL))
+	}
+}
+
+// evictIdleKeys drops every per-client bucket that has been idle since
+// cutoff.
+func (l *Limiter) evictIdleKeys(cutoff time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, b := range l.perKey {
+		if b.idleSince(cutoff) {
+			delete(l.perKey, key)
+		}
+	}
+}
+
+func (l *Limiter) bucketFor(key string) *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.perKey[key]
+	if !ok {
+		b = newTokenBucket(l.perKeyRPS, l.perKeyBurst)
+		l.perKey[key] = b
+	}
+	return b
+}
+
+// clientKey identifies the caller for per-client quotas: the X-API-Key
+// header if set, otherwise the request's remote IP.
+func clientKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Middleware rejects requests that exceed either the global or the calling
+// client's quota with 429, before the handler (and the store mutex behind
+// it) is ever reached.
+func (l *Limiter) Middleware() router.Middleware {
+	return func(next router.HandlerFunc) router.HandlerFunc {
+		return func(c *router.Context) {
+			if !l.global.Take() {
+				l.metrics.rejected.Add(1)
+				c.Writer.Header().Set("Retry-After", "1")
+				http.Error(c.Writer, "Server rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+
+			key := clientKey(c.Request)
+			if !l.bucketFor(key).Take() {
+				l.metrics.throttled.Add(1)
+				c.Writer.Header().Set("Retry-After", "1")
+				http.Error(c.Writer, "Client rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+
+			l.metrics.accepted.Add(1)
+			next(c)
+		}
+	}
+}
+
+// handleMetrics handles GET /metrics, reporting accepted/throttled/rejected
+// request counts since startup.
+func (l *Limiter) handleMetrics(c *router.Context) {
+	c.Writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(c.Writer).Encode(struct {
+		Accepted  int64 `json:"accepted"`
+		Throttled int64 `json:"throttled"`
+		Rejected  int64 `json:"rejected"`
+	}{
+		Accepted:  l.metrics.accepted.Load(),
+		Throttled: l.metrics.throttled.Load(),
+		Rejected:  l.metrics.rejected.Load(),
+	})
+}
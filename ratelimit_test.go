@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucketExhaustsAndRefills(t *testing.T) {
+	b := newTokenBucket(10, 2) // 10/s, burst of 2
+
+	if !b.Take() || !b.Take() {
+		t.Fatal("expected the initial burst of 2 tokens to be available")
+	}
+	if b.Take() {
+		t.Fatal("expected the bucket to be exhausted after the burst")
+	}
+
+	// Backdate lastRefill so Take() sees enough elapsed time to refill
+	// without the test itself sleeping for a full second.
+	b.mu.Lock()
+	b.lastRefill = time.Now().Add(-200 * time.Millisecond)
+	b.mu.Unlock()
+
+	if !b.Take() {
+		t.Fatal("expected a token to be available after enough time elapsed to refill")
+	}
+}
+
+func TestTokenBucketNeverExceedsBurst(t *testing.T) {
+	b := newTokenBucket(1000, 2)
+
+	b.mu.Lock()
+	b.lastRefill = time.Now().Add(-time.Hour)
+	b.mu.Unlock()
+
+	taken := 0
+	for i := 0; i < 5; i++ {
+		if b.Take() {
+			taken++
+		}
+	}
+	if taken != 2 {
+		t.Fatalf("took %d tokens after a long idle period, want burst of 2", taken)
+	}
+}
+
+func TestLimiterEvictsIdleKeys(t *testing.T) {
+	l := NewLimiter(100, 10, 10)
+
+	b := l.bucketFor("client-a")
+	b.mu.Lock()
+	b.lastRefill = time.Now().Add(-2 * perKeyIdleTTL)
+	b.mu.Unlock()
+
+	l.evictIdleKeys(time.Now().Add(-perKeyIdleTTL))
+	l.mu.Lock()
+	_, stillPresent := l.perKey["client-a"]
+	l.mu.Unlock()
+
+	if stillPresent {
+		t.Fatal("expected the idle client's bucket to be evicted")
+	}
+}
@@ -0,0 +1,157 @@
+package router
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Logger logs the method, path, status and duration of every request.
+func Logger() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+			c.Writer = sw
+
+			next(c)
+
+			log.Printf("%s %s %d %s", c.Request.Method, c.Request.URL.Path, sw.status, time.Since(start))
+		}
+	}
+}
+
+// statusWriter records the status code a handler wrote, for Logger.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Hijack passes through to the wrapped ResponseWriter so routes that upgrade
+// the connection (e.g. ws.Upgrade) still work with Logger in the chain.
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("router: underlying ResponseWriter does not support Hijack")
+	}
+	return hj.Hijack()
+}
+
+// Recovery recovers a panicking handler and responds 500 instead of
+// crashing the server.
+func Recovery() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					log.Printf("panic handling %s %s: %v", c.Request.Method, c.Request.URL.Path, rec)
+					http.Error(c.Writer, "Internal server error", http.StatusInternalServerError)
+				}
+			}()
+			next(c)
+		}
+	}
+}
+
+// CORS allows cross-origin requests from any origin, answering preflight
+// OPTIONS requests directly.
+func CORS() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+			c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+			if c.Request.Method == http.MethodOptions {
+				c.Writer.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next(c)
+		}
+	}
+}
+
+// gzipWriter wraps an http.ResponseWriter, compressing everything written
+// to it through w.
+type gzipWriter struct {
+	http.ResponseWriter
+	w *gzip.Writer
+}
+
+func (g *gzipWriter) Write(b []byte) (int, error) {
+	return g.w.Write(b)
+}
+
+// Hijack passes through to the wrapped ResponseWriter so routes that upgrade
+// the connection (e.g. ws.Upgrade) still work with Gzip in the chain.
+func (g *gzipWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := g.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("router: underlying ResponseWriter does not support Hijack")
+	}
+	return hj.Hijack()
+}
+
+// Gzip compresses the response body when the client sends
+// "Accept-Encoding: gzip".
+func Gzip() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			if !strings.Contains(c.Request.Header.Get("Accept-Encoding"), "gzip") {
+				next(c)
+				return
+			}
+
+			c.Writer.Header().Set("Content-Encoding", "gzip")
+			gw := gzip.NewWriter(c.Writer)
+			defer gw.Close()
+
+			c.Writer = &gzipWriter{ResponseWriter: c.Writer, w: gw}
+			next(c)
+		}
+	}
+}
+
+// RateLimit is a simple fixed-window limiter shared across all requests: it
+// allows up to rps requests per one-second window and responds 429 past
+// that. It's intentionally naive; callers that need per-client quotas
+// should reach for a dedicated token-bucket limiter instead.
+func RateLimit(rps int) Middleware {
+	var (
+		mu        sync.Mutex
+		count     int
+		windowEnd = time.Now().Add(time.Second)
+	)
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			mu.Lock()
+			now := time.Now()
+			if now.After(windowEnd) {
+				count = 0
+				windowEnd = now.Add(time.Second)
+			}
+			count++
+			over := count > rps
+			mu.Unlock()
+
+			if over {
+				c.Writer.Header().Set("Retry-After", "1")
+				http.Error(c.Writer, fmt.Sprintf("Rate limit exceeded (%d req/s)", rps), http.StatusTooManyRequests)
+				return
+			}
+			next(c)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Package router is a small radix-style HTTP router: each path segment is a
+// trie node (static or named param; there are no catch-all segments), so "/items/:id" and
+// "/items/:id/history" resolve to distinct routes instead of the caller
+// slicing r.URL.Path by hand. It also carries a middleware chain so
+// cross-cutting concerns (logging, recovery, CORS, ...) don't have to be
+// duplicated in every handler.
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// HandlerFunc is a route handler. It receives a *Context instead of the raw
+// (http.ResponseWriter, *http.Request) pair so it can read path params.
+type HandlerFunc func(*Context)
+
+// Middleware wraps a HandlerFunc to run code before/after it.
+type Middleware func(HandlerFunc) HandlerFunc
+
+// Param is one matched path parameter, e.g. {"id", "42"} for "/items/:id".
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Context carries the request/response pair plus the params matched for
+// this request. Contexts are pooled by Router, so a *Context is only valid
+// for the duration of the handler call it was passed to.
+type Context struct {
+	Writer  http.ResponseWriter
+	Request *http.Request
+
+	params    []Param
+	paramVals []string // scratch buffer for match; zipped into params once the route's names are known
+}
+
+// Param returns the value of a named path param, or "" if it wasn't matched.
+func (c *Context) Param(key string) string {
+	for _, p := range c.params {
+		if p.Key == key {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// ParamInt returns a named path param parsed as an int. This is where
+// strconv.Atoi(idStr) now lives instead of in every handler.
+func (c *Context) ParamInt(key string) (int, error) {
+	return strconv.Atoi(c.Param(key))
+}
+
+type node struct {
+	segment  string
+	isParam  bool // true if this node matches a single path segment by position, not value
+	children []*node
+	handlers map[string]*route
+}
+
+// route is a registered handler plus the param names for its own
+// registration path, in the order their segments appear. Two routes can
+// share the same param node structurally (e.g. "/lobbies/:phrase/join" and
+// "/lobbies/:id/stream" both have a single param segment after "lobbies")
+// while still calling that segment by different names.
+type route struct {
+	handler    HandlerFunc
+	paramNames []string
+}
+
+func (n *node) staticChild(segment string) *node {
+	for _, c := range n.children {
+		if !c.isParam && c.segment == segment {
+			return c
+		}
+	}
+	return nil
+}
+
+func (n *node) paramChild() *node {
+	for _, c := range n.children {
+		if c.isParam {
+			return c
+		}
+	}
+	return nil
+}
+
+// Router is a radix-style mux with a middleware chain applied to every
+// matched route.
+type Router struct {
+	root        *node
+	middlewares []Middleware
+	pool        sync.Pool
+}
+
+// New returns an empty Router.
+func New() *Router {
+	r := &Router{root: &node{}}
+	r.pool.New = func() any {
+		// Preallocate a handful of param slots so the common case (one or
+		// two path params) never allocates on the hot path.
+		return &Context{params: make([]Param, 0, 8), paramVals: make([]string, 0, 8)}
+	}
+	return r
+}
+
+// Use appends middleware to the chain every matched route runs through, in
+// the order given (the first one wraps the rest).
+func (r *Router) Use(mw ...Middleware) {
+	r.middlewares = append(r.middlewares, mw...)
+}
+
+func (r *Router) handle(method, path string, h HandlerFunc) {
+	segments := splitPath(path)
+
+	cur := r.root
+	var paramNames []string
+	for _, seg := range segments {
+		isParam := strings.HasPrefix(seg, ":")
+
+		var next *node
+		if isParam {
+			next = cur.paramChild()
+		} else {
+			next = cur.staticChild(seg)
+		}
+		if next == nil {
+			next = &node{segment: seg, isParam: isParam}
+			cur.children = append(cur.children, next)
+		}
+		if isParam {
+			paramNames = append(paramNames, seg[1:])
+		}
+		cur = next
+	}
+
+	if cur.handlers == nil {
+		cur.handlers = make(map[string]*route)
+	}
+	cur.handlers[method] = &route{handler: h, paramNames: paramNames}
+}
+
+// GET registers h for GET requests to path.
+func (r *Router) GET(path string, h HandlerFunc) { r.handle(http.MethodGet, path, h) }
+
+// POST registers h for POST requests to path.
+func (r *Router) POST(path string, h HandlerFunc) { r.handle(http.MethodPost, path, h) }
+
+// PUT registers h for PUT requests to path.
+func (r *Router) PUT(path string, h HandlerFunc) { r.handle(http.MethodPut, path, h) }
+
+// DELETE registers h for DELETE requests to path.
+func (r *Router) DELETE(path string, h HandlerFunc) { r.handle(http.MethodDelete, path, h) }
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// match walks the trie for path, appending the value matched at each param
+// segment (in order) into dst, whose capacity the caller preallocated to
+// avoid allocating on every request. The segments' names aren't resolved
+// here: a shared param node can mean different things to different routes
+// (e.g. "/lobbies/:phrase/join" vs "/lobbies/:id/stream"), so naming is the
+// matched route's job, once ServeHTTP knows which method's route it is.
+func (r *Router) match(path string, dst []string) (*node, []string) {
+	cur := r.root
+	for _, seg := range splitPath(path) {
+		next := cur.staticChild(seg)
+		if next == nil {
+			next = cur.paramChild()
+			if next == nil {
+				return nil, dst
+			}
+			dst = append(dst, seg)
+		}
+		cur = next
+	}
+	if cur.handlers == nil {
+		return nil, dst
+	}
+	return cur, dst
+}
+
+// ServeHTTP implements http.Handler: it matches the route, then always runs
+// the middleware chain before dispatching — including around the 404/405
+// fallbacks, so Recovery/CORS/rate-limiting still apply to requests that
+// don't match any route.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := r.pool.Get().(*Context)
+	ctx.Writer = w
+	ctx.Request = req
+	ctx.params = ctx.params[:0]
+	ctx.paramVals = ctx.paramVals[:0]
+	defer r.pool.Put(ctx)
+
+	n, vals := r.match(req.URL.Path, ctx.paramVals)
+	ctx.paramVals = vals
+
+	var h HandlerFunc
+	switch {
+	case n == nil:
+		h = func(c *Context) { http.NotFound(c.Writer, c.Request) }
+	default:
+		rt, ok := n.handlers[req.Method]
+		if !ok {
+			h = func(c *Context) { http.Error(c.Writer, "Method not allowed", http.StatusMethodNotAllowed) }
+			break
+		}
+		for i, name := range rt.paramNames {
+			ctx.params = append(ctx.params, Param{Key: name, Value: vals[i]})
+		}
+		h = rt.handler
+	}
+
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		h = r.middlewares[i](h)
+	}
+	h(ctx)
+}
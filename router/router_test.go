@@ -0,0 +1,84 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouterDistinguishesParamFromNestedRoute(t *testing.T) {
+	r := New()
+	r.GET("/items/:id", func(c *Context) {
+		c.Writer.Write([]byte("item:" + c.Param("id")))
+	})
+	r.GET("/items/:id/history", func(c *Context) {
+		c.Writer.Write([]byte("history:" + c.Param("id")))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/42", nil))
+	if got := w.Body.String(); got != "item:42" {
+		t.Fatalf("GET /items/42 = %q, want %q", got, "item:42")
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/42/history", nil))
+	if got := w.Body.String(); got != "history:42" {
+		t.Fatalf("GET /items/42/history = %q, want %q", got, "history:42")
+	}
+}
+
+func TestRouterResolvesParamNamesPerRoute(t *testing.T) {
+	r := New()
+	r.POST("/lobbies/:phrase/join", func(c *Context) {
+		c.Writer.Write([]byte("join:" + c.Param("phrase")))
+	})
+	r.GET("/lobbies/:id/stream", func(c *Context) {
+		c.Writer.Write([]byte("stream:" + c.Param("id")))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/lobbies/abc123/stream", nil))
+	if got := w.Body.String(); got != "stream:abc123" {
+		t.Fatalf("GET /lobbies/abc123/stream = %q, want %q", got, "stream:abc123")
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/lobbies/abc123/join", nil))
+	if got := w.Body.String(); got != "join:abc123" {
+		t.Fatalf("POST /lobbies/abc123/join = %q, want %q", got, "join:abc123")
+	}
+}
+
+func TestRouterMethodNotAllowed(t *testing.T) {
+	r := New()
+	r.GET("/items", func(c *Context) {})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestRouterNotFoundStillRunsMiddleware(t *testing.T) {
+	r := New()
+	var ran bool
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			ran = true
+			next(c)
+		}
+	})
+	r.GET("/items", func(c *Context) {})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/nope", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if !ran {
+		t.Fatal("middleware did not run for an unmatched route")
+	}
+}
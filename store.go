@@ -0,0 +1,408 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// EventType describes the kind of mutation a Watch subscriber observes.
+type EventType string
+
+const (
+	EventCreated EventType = "created"
+	EventUpdated EventType = "updated"
+	EventDeleted EventType = "deleted"
+)
+
+// Event is published to Watch subscribers whenever the store changes.
+type Event struct {
+	Type EventType `json:"type"`
+	Item Item      `json:"item"`
+}
+
+// Store is the persistence boundary for items. The items handlers call
+// through this interface so the backing implementation (in-memory, on-disk,
+// ...) can be swapped without touching the HTTP layer. Every method takes a
+// context so a store call in flight when the client disconnects, or past
+// its per-route deadline, can be abandoned instead of run to completion.
+type Store interface {
+	List(ctx context.Context) ([]Item, error)
+	Get(ctx context.Context, id int) (Item, error)
+	Create(ctx context.Context, item Item) (Item, error)
+	Update(ctx context.Context, id int, item Item) (Item, error)
+	Delete(ctx context.Context, id int) error
+	Watch(ctx context.Context) (<-chan Event, error)
+}
+
+// ErrNotFound is returned by Get/Update/Delete when the item doesn't exist.
+var ErrNotFound = fmt.Errorf("item not found")
+
+// MemoryStore is a Store backed by a plain map. It matches the behavior of
+// the original package-level items/mu/idSeq globals it replaces.
+type MemoryStore struct {
+	mu    sync.Mutex
+	items map[int]Item
+	idSeq int
+	subs  []chan Event
+}
+
+// NewMemoryStore returns an empty in-memory Store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		items: make(map[int]Item),
+		idSeq: 1,
+	}
+}
+
+func (s *MemoryStore) List(ctx context.Context) ([]Item, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]Item, 0, len(s.items))
+	for _, item := range s.items {
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, id int) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[id]
+	if !ok {
+		return Item{}, ErrNotFound
+	}
+	return item, nil
+}
+
+func (s *MemoryStore) Create(ctx context.Context, item Item) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, err
+	}
+
+	s.mu.Lock()
+	item.ID = s.idSeq
+	s.items[item.ID] = item
+	s.idSeq++
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventCreated, Item: item})
+	return item, nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, id int, item Item) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, err
+	}
+
+	s.mu.Lock()
+	if _, ok := s.items[id]; !ok {
+		s.mu.Unlock()
+		return Item{}, ErrNotFound
+	}
+	item.ID = id
+	s.items[id] = item
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventUpdated, Item: item})
+	return item, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	item, ok := s.items[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrNotFound
+	}
+	delete(s.items, id)
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventDeleted, Item: item})
+	return nil
+}
+
+// Watch returns a channel of events that stays open until ctx is done, at
+// which point it's unsubscribed and closed.
+func (s *MemoryStore) Watch(ctx context.Context) (<-chan Event, error) {
+	s.mu.Lock()
+	ch := make(chan Event, 16)
+	s.subs = append(s.subs, ch)
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.unsubscribe(ch)
+	}()
+	return ch, nil
+}
+
+func (s *MemoryStore) unsubscribe(ch chan Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, sub := range s.subs {
+		if sub == ch {
+			s.subs = append(s.subs[:i], s.subs[i+1:]...)
+			close(ch)
+			return
+		}
+	}
+}
+
+func (s *MemoryStore) publish(ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ch := range s.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// record is one line of the FileStore's on-disk mutation log. Replaying the
+// records in order reconstructs idSeq and the current item set, the same
+// way the moth server replays its points log on boot.
+type record struct {
+	Op   EventType `json:"op"`
+	ID   int       `json:"id"`
+	Item Item      `json:"item,omitempty"`
+}
+
+// FileStore is a Store backed by a durable, line-oriented append log. Every
+// accepted mutation is written and fsync'd before the call returns, so a
+// crash right after a 201 can't lose the write; on boot the log is replayed
+// to rebuild state in memory.
+type FileStore struct {
+	mu    sync.Mutex
+	items map[int]Item
+	idSeq int
+	subs  []chan Event
+	f     *os.File
+}
+
+// NewFileStore opens (creating if necessary) the mutation log under dir and
+// replays it to reconstruct the current item set.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create state dir: %w", err)
+	}
+
+	path := filepath.Join(dir, "items.log")
+	s := &FileStore{
+		items: make(map[int]Item),
+		idSeq: 1,
+	}
+
+	if err := s.replay(path); err != nil {
+		return nil, fmt.Errorf("replay state log: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open state log: %w", err)
+	}
+	s.f = f
+	return s, nil
+}
+
+func (s *FileStore) replay(path string) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fmt.Errorf("corrupt record: %w", err)
+		}
+
+		switch rec.Op {
+		case EventCreated, EventUpdated:
+			s.items[rec.Item.ID] = rec.Item
+			if rec.Item.ID >= s.idSeq {
+				s.idSeq = rec.Item.ID + 1
+			}
+		case EventDeleted:
+			delete(s.items, rec.ID)
+		}
+	}
+	return scanner.Err()
+}
+
+// append writes rec to the log and fsyncs before returning, so the record is
+// durable by the time the caller acknowledges the request.
+func (s *FileStore) append(rec record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if _, err := s.f.Write(line); err != nil {
+		return err
+	}
+	return s.f.Sync()
+}
+
+func (s *FileStore) List(ctx context.Context) ([]Item, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]Item, 0, len(s.items))
+	for _, item := range s.items {
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+func (s *FileStore) Get(ctx context.Context, id int) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[id]
+	if !ok {
+		return Item{}, ErrNotFound
+	}
+	return item, nil
+}
+
+func (s *FileStore) Create(ctx context.Context, item Item) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, err
+	}
+
+	s.mu.Lock()
+	item.ID = s.idSeq
+	rec := record{Op: EventCreated, ID: item.ID, Item: item}
+	if err := s.append(rec); err != nil {
+		s.mu.Unlock()
+		return Item{}, fmt.Errorf("persist create: %w", err)
+	}
+	s.items[item.ID] = item
+	s.idSeq++
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventCreated, Item: item})
+	return item, nil
+}
+
+func (s *FileStore) Update(ctx context.Context, id int, item Item) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, err
+	}
+
+	s.mu.Lock()
+	if _, ok := s.items[id]; !ok {
+		s.mu.Unlock()
+		return Item{}, ErrNotFound
+	}
+	item.ID = id
+	rec := record{Op: EventUpdated, ID: id, Item: item}
+	if err := s.append(rec); err != nil {
+		s.mu.Unlock()
+		return Item{}, fmt.Errorf("persist update: %w", err)
+	}
+	s.items[id] = item
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventUpdated, Item: item})
+	return item, nil
+}
+
+func (s *FileStore) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	item, ok := s.items[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrNotFound
+	}
+	rec := record{Op: EventDeleted, ID: id}
+	if err := s.append(rec); err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("persist delete: %w", err)
+	}
+	delete(s.items, id)
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventDeleted, Item: item})
+	return nil
+}
+
+// Watch returns a channel of events that stays open until ctx is done, at
+// which point it's unsubscribed and closed.
+func (s *FileStore) Watch(ctx context.Context) (<-chan Event, error) {
+	s.mu.Lock()
+	ch := make(chan Event, 16)
+	s.subs = append(s.subs, ch)
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.unsubscribe(ch)
+	}()
+	return ch, nil
+}
+
+func (s *FileStore) unsubscribe(ch chan Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, sub := range s.subs {
+		if sub == ch {
+			s.subs = append(s.subs[:i], s.subs[i+1:]...)
+			close(ch)
+			return
+		}
+	}
+}
+
+func (s *FileStore) publish(ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ch := range s.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
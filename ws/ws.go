@@ -0,0 +1,283 @@
+// Package ws is a minimal RFC 6455 WebSocket implementation: just enough
+// handshake and text-frame (un)framing to carry JSON snapshots and input
+// frames between the lobby server and its clients, without pulling in a
+// third-party dependency for it.
+package ws
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+const magicGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// Conn is an upgraded WebSocket connection. Reads and writes are frame-at-a-
+// time text messages; callers round-trip JSON over them.
+type Conn struct {
+	rw     *bufio.ReadWriter
+	c      net.Conn
+	masked bool // true for client-side connections; clients must mask per RFC 6455
+}
+
+// Upgrade performs the WebSocket handshake over an existing HTTP request,
+// hijacking the underlying connection. The caller must not write to w after
+// calling Upgrade.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" || r.Header.Get("Upgrade") != "websocket" {
+		return nil, errors.New("ws: not a websocket upgrade request")
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("ws: response writer does not support hijacking")
+	}
+	netConn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("ws: hijack: %w", err)
+	}
+
+	accept := acceptKey(key)
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + accept + "\r\n\r\n"
+	if _, err := rw.WriteString(resp); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	return &Conn{rw: rw, c: netConn}, nil
+}
+
+// Dial opens a client WebSocket connection to a ws:// URL, such as
+// "ws://localhost:8080/lobbies/<id>/stream?player_id=<id>".
+func Dial(rawURL string) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("ws: parse url: %w", err)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "80")
+	}
+	netConn, err := net.Dial("tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("ws: dial: %w", err)
+	}
+
+	key, err := clientKey()
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	requestURI := u.RequestURI()
+	req := "GET " + requestURI + " HTTP/1.1\r\n" +
+		"Host: " + u.Host + "\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := netConn.Write([]byte(req)); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	br := bufio.NewReader(netConn)
+	resp, err := http.ReadResponse(br, &http.Request{Method: http.MethodGet})
+	if err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("ws: read handshake response: %w", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		netConn.Close()
+		return nil, fmt.Errorf("ws: handshake rejected: %s", resp.Status)
+	}
+
+	rw := bufio.NewReadWriter(br, bufio.NewWriter(netConn))
+	return &Conn{rw: rw, c: netConn, masked: true}, nil
+}
+
+func clientKey() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("ws: generate key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+func acceptKey(key string) string {
+	h := sha1.New()
+	io.WriteString(h, key+magicGUID)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// Close closes the underlying connection.
+func (c *Conn) Close() error {
+	return c.c.Close()
+}
+
+// WriteMessage sends payload as a single unfragmented text frame. Client
+// connections (opened with Dial) mask it per RFC 6455; server connections
+// (from Upgrade) never mask outgoing frames.
+func (c *Conn) WriteMessage(payload []byte) error {
+	if err := writeFrame(c.rw.Writer, opText, payload, c.masked); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+func writeFrame(w *bufio.Writer, opcode byte, payload []byte, masked bool) error {
+	first := byte(0x80 | opcode) // FIN + opcode
+	if err := w.WriteByte(first); err != nil {
+		return err
+	}
+
+	maskBit := byte(0)
+	if masked {
+		maskBit = 0x80
+	}
+
+	n := len(payload)
+	switch {
+	case n <= 125:
+		if err := w.WriteByte(maskBit | byte(n)); err != nil {
+			return err
+		}
+	case n <= 0xFFFF:
+		if err := w.WriteByte(maskBit | 126); err != nil {
+			return err
+		}
+		var sz [2]byte
+		binary.BigEndian.PutUint16(sz[:], uint16(n))
+		if _, err := w.Write(sz[:]); err != nil {
+			return err
+		}
+	default:
+		if err := w.WriteByte(maskBit | 127); err != nil {
+			return err
+		}
+		var sz [8]byte
+		binary.BigEndian.PutUint64(sz[:], uint64(n))
+		if _, err := w.Write(sz[:]); err != nil {
+			return err
+		}
+	}
+
+	if !masked {
+		_, err := w.Write(payload)
+		return err
+	}
+
+	var key [4]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		return fmt.Errorf("ws: generate mask: %w", err)
+	}
+	if _, err := w.Write(key[:]); err != nil {
+		return err
+	}
+	maskedPayload := make([]byte, n)
+	for i, b := range payload {
+		maskedPayload[i] = b ^ key[i%4]
+	}
+	_, err := w.Write(maskedPayload)
+	return err
+}
+
+// ReadMessage reads the next text frame and returns its payload. It answers
+// pings transparently, returns io.EOF on a close frame, and skips every
+// other frame (pongs and binary frames included).
+func (c *Conn) ReadMessage() ([]byte, error) {
+	for {
+		opcode, payload, err := readFrame(c.rw.Reader)
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case opText:
+			return payload, nil
+		case opPing:
+			if err := writeFrame(c.rw.Writer, opPong, payload, c.masked); err != nil {
+				return nil, err
+			}
+			if err := c.rw.Flush(); err != nil {
+				return nil, err
+			}
+		case opClose:
+			return nil, io.EOF
+		}
+	}
+}
+
+func readFrame(r *bufio.Reader) (byte, []byte, error) {
+	head, err := readN(r, 2)
+	if err != nil {
+		return 0, nil, err
+	}
+	opcode := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+
+	switch length {
+	case 126:
+		ext, err := readN(r, 2)
+		if err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext, err := readN(r, 8)
+		if err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	var maskKey [4]byte
+	if masked {
+		k, err := readN(r, 4)
+		if err != nil {
+			return 0, nil, err
+		}
+		copy(maskKey[:], k)
+	}
+
+	payload, err := readN(r, int(length))
+	if err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+	return opcode, payload, nil
+}
+
+func readN(r *bufio.Reader, n int) ([]byte, error) {
+	buf := make([]byte, n)
+	_, err := io.ReadFull(r, buf)
+	return buf, err
+}